@@ -0,0 +1,247 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReceiptPagedRequestCommand decodes and validates the query params for
+// GET /receipts. Keeping the decode/validate logic here instead of in the
+// handler makes it unit-testable without spinning up an HTTP server.
+type ReceiptPagedRequestCommand struct {
+	Page             int
+	PageSize         int
+	SortBy           string
+	OrderBy          string
+	RetailerContains string
+	MinPoints        *int
+	MaxPoints        *int
+	DateFrom         *time.Time
+	DateTo           *time.Time
+}
+
+var validSortBy = map[string]bool{"points": true, "date": true, "retailer": true}
+var validOrderBy = map[string]bool{"asc": true, "desc": true}
+
+// maxPage bounds the page param so (page-1)*pageSize can't overflow int.
+const maxPage = 1_000_000
+
+// LoadDataFromRequest parses and validates the query string into the
+// command, returning an error describing the first problem found.
+func (c *ReceiptPagedRequestCommand) LoadDataFromRequest(w http.ResponseWriter, r *http.Request) error {
+	q := r.URL.Query()
+
+	c.Page = 1
+	if v := q.Get("page"); v != "" {
+		page, err := strconv.Atoi(v)
+		if err != nil || page < 1 || page > maxPage {
+			return fmt.Errorf("page must be between 1 and %d", maxPage)
+		}
+		c.Page = page
+	}
+
+	c.PageSize = 20
+	if v := q.Get("pageSize"); v != "" {
+		pageSize, err := strconv.Atoi(v)
+		if err != nil || pageSize < 1 || pageSize > 200 {
+			return fmt.Errorf("pageSize must be between 1 and 200")
+		}
+		c.PageSize = pageSize
+	}
+
+	c.SortBy = "date"
+	if v := q.Get("sortBy"); v != "" {
+		if !validSortBy[v] {
+			return fmt.Errorf("sortBy must be one of points, date, retailer")
+		}
+		c.SortBy = v
+	}
+
+	c.OrderBy = "asc"
+	if v := q.Get("orderBy"); v != "" {
+		if !validOrderBy[v] {
+			return fmt.Errorf("orderBy must be one of asc, desc")
+		}
+		c.OrderBy = v
+	}
+
+	c.RetailerContains = q.Get("retailerContains")
+
+	if v := q.Get("minPoints"); v != "" {
+		minPoints, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("minPoints must be an integer")
+		}
+		c.MinPoints = &minPoints
+	}
+
+	if v := q.Get("maxPoints"); v != "" {
+		maxPoints, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("maxPoints must be an integer")
+		}
+		c.MaxPoints = &maxPoints
+	}
+
+	if v := q.Get("dateFrom"); v != "" {
+		dateFrom, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return fmt.Errorf("dateFrom must match 2006-01-02")
+		}
+		c.DateFrom = &dateFrom
+	}
+
+	if v := q.Get("dateTo"); v != "" {
+		dateTo, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return fmt.Errorf("dateTo must match 2006-01-02")
+		}
+		c.DateTo = &dateTo
+	}
+
+	return nil
+}
+
+// PagedReceiptsResponse is the JSON shape returned by GET /receipts.
+type PagedReceiptsResponse struct {
+	Receipts []Receipt `json:"receipts"`
+	Page     int       `json:"page"`
+	PageSize int       `json:"pageSize"`
+	Total    int       `json:"total"`
+}
+
+// Execute filters, sorts, and paginates the given receipts according to
+// the command's parameters.
+func (c *ReceiptPagedRequestCommand) Execute(all []Receipt) PagedReceiptsResponse {
+	filtered := make([]Receipt, 0, len(all))
+	for _, r := range all {
+		if c.RetailerContains != "" && !containsFold(r.Retailer, c.RetailerContains) {
+			continue
+		}
+		if c.MinPoints != nil && r.Points < *c.MinPoints {
+			continue
+		}
+		if c.MaxPoints != nil && r.Points > *c.MaxPoints {
+			continue
+		}
+		if c.DateFrom != nil || c.DateTo != nil {
+			date, err := time.Parse("2006-01-02", r.PurchaseDate)
+			if err != nil {
+				continue
+			}
+			if c.DateFrom != nil && date.Before(*c.DateFrom) {
+				continue
+			}
+			if c.DateTo != nil && date.After(*c.DateTo) {
+				continue
+			}
+		}
+		filtered = append(filtered, r)
+	}
+
+	sort.Slice(filtered, func(i, j int) bool {
+		var less bool
+		switch c.SortBy {
+		case "points":
+			less = filtered[i].Points < filtered[j].Points
+		case "retailer":
+			less = filtered[i].Retailer < filtered[j].Retailer
+		default:
+			less = filtered[i].PurchaseDate+filtered[i].PurchaseTime < filtered[j].PurchaseDate+filtered[j].PurchaseTime
+		}
+		if c.OrderBy == "desc" {
+			return !less
+		}
+		return less
+	})
+
+	total := len(filtered)
+	start := (c.Page - 1) * c.PageSize
+	if start < 0 || start > total {
+		start = total
+	}
+	end := start + c.PageSize
+	if end < start || end > total {
+		end = total
+	}
+
+	return PagedReceiptsResponse{
+		Receipts: filtered[start:end],
+		Page:     c.Page,
+		PageSize: c.PageSize,
+		Total:    total,
+	}
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// BulkStatusUpdateCommand applies a status change to a batch of receipts.
+type BulkStatusUpdateCommand struct {
+	ReceiptIDs []string `json:"receiptIds"`
+	Status     string   `json:"status"`
+	Comment    string   `json:"comment"`
+}
+
+var validBulkStatus = map[string]bool{"flag": true, "archive": true, "delete": true}
+
+// LoadDataFromRequest decodes and validates the JSON body of a
+// POST /receipts/bulk-status request.
+func (c *BulkStatusUpdateCommand) LoadDataFromRequest(w http.ResponseWriter, r *http.Request) error {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)
+	if err := json.NewDecoder(r.Body).Decode(c); err != nil {
+		return err
+	}
+	if len(c.ReceiptIDs) == 0 {
+		return fmt.Errorf("receiptIds must not be empty")
+	}
+	if !validBulkStatus[c.Status] {
+		return fmt.Errorf("status must be one of flag, archive, delete")
+	}
+	return nil
+}
+
+// BulkStatusUpdateResult reports which receipts were updated and which
+// could not be found.
+type BulkStatusUpdateResult struct {
+	Updated  []string `json:"updated"`
+	NotFound []string `json:"notFound"`
+}
+
+// Execute applies the status change to every requested receipt in s.
+func (c *BulkStatusUpdateCommand) Execute(s Store) (BulkStatusUpdateResult, error) {
+	result := BulkStatusUpdateResult{}
+
+	for _, id := range c.ReceiptIDs {
+		receipt, ok, err := s.Get(id)
+		if err != nil {
+			return result, err
+		}
+		if !ok {
+			result.NotFound = append(result.NotFound, id)
+			continue
+		}
+
+		if c.Status == "delete" {
+			if err := s.Delete(id); err != nil {
+				return result, err
+			}
+		} else {
+			receipt.Status = c.Status
+			receipt.StatusComment = c.Comment
+			if err := s.Save(id, receipt); err != nil {
+				return result, err
+			}
+		}
+
+		result.Updated = append(result.Updated, id)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReceiptPagedRequestCommandDefaults(t *testing.T) {
+	req := httptest.NewRequest("GET", "/receipts", nil)
+
+	var cmd ReceiptPagedRequestCommand
+	if err := cmd.LoadDataFromRequest(nil, req); err != nil {
+		t.Fatalf("LoadDataFromRequest returned error: %v", err)
+	}
+
+	if cmd.Page != 1 || cmd.PageSize != 20 || cmd.SortBy != "date" || cmd.OrderBy != "asc" {
+		t.Fatalf("unexpected defaults: %+v", cmd)
+	}
+}
+
+func TestReceiptPagedRequestCommandValidation(t *testing.T) {
+	cases := []string{
+		"?page=0",
+		"?pageSize=0",
+		"?pageSize=9999",
+		"?sortBy=bogus",
+		"?orderBy=bogus",
+		"?minPoints=abc",
+		"?dateFrom=not-a-date",
+		"?page=50000000000000000",
+	}
+
+	for _, query := range cases {
+		req := httptest.NewRequest("GET", "/receipts"+query, nil)
+		var cmd ReceiptPagedRequestCommand
+		if err := cmd.LoadDataFromRequest(nil, req); err == nil {
+			t.Errorf("query %q: expected an error, got none", query)
+		}
+	}
+}
+
+func TestReceiptPagedRequestCommandExecute(t *testing.T) {
+	all := []Receipt{
+		{ID: "a", Retailer: "Target", Points: 10, PurchaseDate: "2022-01-01"},
+		{ID: "b", Retailer: "Walgreens", Points: 30, PurchaseDate: "2022-01-02"},
+		{ID: "c", Retailer: "Target Express", Points: 20, PurchaseDate: "2022-01-03"},
+	}
+
+	cmd := ReceiptPagedRequestCommand{
+		Page: 1, PageSize: 20, SortBy: "points", OrderBy: "desc",
+		RetailerContains: "target",
+	}
+
+	result := cmd.Execute(all)
+
+	if result.Total != 2 {
+		t.Fatalf("expected 2 receipts to match the retailer filter, got %d", result.Total)
+	}
+	if result.Receipts[0].ID != "c" || result.Receipts[1].ID != "a" {
+		t.Fatalf("expected receipts sorted by points desc, got %+v", result.Receipts)
+	}
+}
+
+func TestReceiptPagedRequestCommandPagination(t *testing.T) {
+	all := []Receipt{
+		{ID: "a", Points: 1}, {ID: "b", Points: 2}, {ID: "c", Points: 3},
+	}
+	cmd := ReceiptPagedRequestCommand{Page: 2, PageSize: 2, SortBy: "points", OrderBy: "asc"}
+
+	result := cmd.Execute(all)
+
+	if len(result.Receipts) != 1 || result.Receipts[0].ID != "c" {
+		t.Fatalf("expected page 2 to contain just the last receipt, got %+v", result.Receipts)
+	}
+}
+
+func TestReceiptPagedRequestCommandExecuteOutOfRangePage(t *testing.T) {
+	all := []Receipt{{ID: "a", Points: 1}, {ID: "b", Points: 2}}
+
+	cases := []ReceiptPagedRequestCommand{
+		// A page far beyond the data set must clamp to an empty result
+		// instead of panicking on an out-of-bounds slice.
+		{Page: 1_000_000, PageSize: 200, SortBy: "points", OrderBy: "asc"},
+		// A page large enough that (page-1)*pageSize overflows int and
+		// wraps negative must clamp rather than panic on a negative slice
+		// bound.
+		{Page: 46116860184273881, PageSize: 200, SortBy: "points", OrderBy: "asc"},
+	}
+
+	for _, cmd := range cases {
+		result := cmd.Execute(all)
+		if len(result.Receipts) != 0 {
+			t.Fatalf("page %d: expected no receipts for an out-of-range page, got %+v", cmd.Page, result.Receipts)
+		}
+		if result.Total != 2 {
+			t.Fatalf("page %d: expected total to reflect the full filtered set, got %d", cmd.Page, result.Total)
+		}
+	}
+}
+
+func TestBulkStatusUpdateCommandValidation(t *testing.T) {
+	cases := []string{
+		`{"receiptIds":[],"status":"flag"}`,
+		`{"receiptIds":["a"],"status":"bogus"}`,
+	}
+
+	for _, body := range cases {
+		req := httptest.NewRequest("POST", "/receipts/bulk-status", bytes.NewBufferString(body))
+		var cmd BulkStatusUpdateCommand
+		if err := cmd.LoadDataFromRequest(httptest.NewRecorder(), req); err == nil {
+			t.Errorf("body %q: expected an error, got none", body)
+		}
+	}
+}
+
+func TestBulkStatusUpdateCommandExecute(t *testing.T) {
+	store := NewMemoryStore()
+	store.Save("a", Receipt{ID: "a"})
+	store.Save("b", Receipt{ID: "b"})
+
+	cmd := BulkStatusUpdateCommand{ReceiptIDs: []string{"a", "missing"}, Status: "flag", Comment: "looks off"}
+	result, err := cmd.Execute(store)
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+
+	if len(result.Updated) != 1 || result.Updated[0] != "a" {
+		t.Fatalf("expected only receipt a to be updated, got %+v", result.Updated)
+	}
+	if len(result.NotFound) != 1 || result.NotFound[0] != "missing" {
+		t.Fatalf("expected missing to be reported not found, got %+v", result.NotFound)
+	}
+
+	updated, _, _ := store.Get("a")
+	if updated.Status != "flag" || updated.StatusComment != "looks off" {
+		t.Fatalf("expected receipt a to carry the new status, got %+v", updated)
+	}
+}
+
+func TestBulkStatusUpdateCommandDelete(t *testing.T) {
+	store := NewMemoryStore()
+	store.Save("a", Receipt{ID: "a"})
+
+	cmd := BulkStatusUpdateCommand{ReceiptIDs: []string{"a"}, Status: "delete"}
+	if _, err := cmd.Execute(store); err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+
+	if _, ok, _ := store.Get("a"); ok {
+		t.Fatal("expected receipt a to be deleted")
+	}
+}
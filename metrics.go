@@ -0,0 +1,71 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	receiptsProcessedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "receipts_processed_total",
+		Help: "Total number of receipts successfully processed.",
+	})
+
+	processingDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "receipt_processing_duration_seconds",
+		Help:    "Time spent validating and scoring a receipt.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	pointsAwarded = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "receipt_points_awarded",
+		Help:    "Distribution of total points awarded per receipt.",
+		Buckets: []float64{0, 10, 25, 50, 75, 100, 150, 200, 300, 500},
+	})
+
+	errorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "receipt_errors_total",
+		Help: "Total number of request errors, by class.",
+	}, []string{"class"})
+
+	ruleFiredTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "receipt_rule_fired_total",
+		Help: "Number of times each scoring rule awarded non-zero points.",
+	}, []string{"rule"})
+
+	rulePointsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "receipt_rule_points_total",
+		Help: "Sum of points contributed by each scoring rule.",
+	}, []string{"rule"})
+)
+
+// recordScoring updates the Prometheus metrics and rolling stats for one
+// scored receipt.
+func recordScoring(duration time.Duration, total int, breakdown []RuleResult) {
+	receiptsProcessedTotal.Inc()
+	processingDuration.Observe(duration.Seconds())
+	pointsAwarded.Observe(float64(total))
+
+	for _, result := range breakdown {
+		if result.Points > 0 {
+			ruleFiredTotal.WithLabelValues(result.Rule).Inc()
+		}
+		rulePointsTotal.WithLabelValues(result.Rule).Add(float64(result.Points))
+		globalStats.Record(result.Rule, result.Points)
+	}
+}
+
+// recordError increments the error counter for the given class
+// ("validation", "internal", ...).
+func recordError(class string) {
+	errorsTotal.WithLabelValues(class).Inc()
+}
+
+// metricsHandler serves Prometheus-formatted metrics at /metrics.
+func metricsHandler() http.Handler {
+	return promhttp.Handler()
+}
@@ -4,23 +4,24 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
+	"flag"
 	"log"
 	"net/http"
-	"strconv"
+	"os"
 	"strings"
-	"sync"
 	"time"
-	"math"
-	"unicode"
 )
 
 type Receipt struct {
-	Retailer     string    `json:"retailer"`
-	PurchaseDate string    `json:"purchaseDate"`
-	PurchaseTime string    `json:"purchaseTime"`
-	Items        []Item    `json:"items"`
-	Total        string    `json:"total"`
-	Points       int       `json:"points,omitempty"`
+	ID            string `json:"id,omitempty"`
+	Retailer      string `json:"retailer"`
+	PurchaseDate  string `json:"purchaseDate"`
+	PurchaseTime  string `json:"purchaseTime"`
+	Items         []Item `json:"items"`
+	Total         string `json:"total"`
+	Points        int    `json:"points,omitempty"`
+	Status        string `json:"status,omitempty"`
+	StatusComment string `json:"statusComment,omitempty"`
 }
 
 type Item struct {
@@ -33,27 +34,77 @@ type PointsResponse struct {
 }
 
 var (
-	mutex    sync.Mutex
-	receipts map[string]Receipt
+	store  Store
+	engine *RuleEngine
 )
 
-// Create receipts map as our in-memory database.
-// Configure our two endpoints.
+// Create the configured Store and RuleEngine, and configure our endpoints.
 func main() {
-	receipts = make(map[string]Receipt)
+	envBackend, envPath := storeBackendFromEnv()
 
+	backend := flag.String("store", envBackend, "storage backend: memory or bolt")
+	path := flag.String("store-path", envPath, "path to the storage file (bolt backend only)")
+	rulesConfigPath := flag.String("rules-config", os.Getenv("RULES_CONFIG_PATH"), "path to a JSON file overriding rule constants")
+	rulesPluginsDir := flag.String("rules-plugins-dir", os.Getenv("RULES_PLUGINS_DIR"), "directory of .so plugins exporting NewRule() Rule")
+	flag.Parse()
+
+	var err error
+	store, err = NewStore(*backend, *path)
+	if err != nil {
+		log.Fatalf("failed to initialize store: %v", err)
+	}
+	if closer, ok := store.(*BoltStore); ok {
+		defer closer.Close()
+	}
+
+	rulesConfig, err := LoadRulesConfig(*rulesConfigPath)
+	if err != nil {
+		log.Fatalf("failed to load rules config: %v", err)
+	}
+	engine = NewRuleEngine(BuiltinRules(rulesConfig)...)
+
+	pluginRules, err := LoadRulePlugins(*rulesPluginsDir)
+	if err != nil {
+		log.Fatalf("failed to load rule plugins: %v", err)
+	}
+	for _, rule := range pluginRules {
+		engine.Register(rule)
+	}
+
+	go globalStats.Start()
+
+	http.HandleFunc("/receipts", ListReceipts)
 	http.HandleFunc("/receipts/process", ProcessReceipt)
+	http.HandleFunc("/receipts/bulk-status", BulkUpdateStatus)
 	http.HandleFunc("/receipts/", GetPointsHandler)
+	http.HandleFunc("/stats", GetStats)
+	http.Handle("/metrics", metricsHandler())
+
+	cfg := serverConfigFromEnv()
+	srv := &http.Server{
+		Addr:              cfg.Addr,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+	}
 
-	log.Println("Server started on port 8000")
-	log.Fatal(http.ListenAndServe(":8000", nil))
+	log.Printf("Server started on %s", cfg.Addr)
+	if err := runServer(srv, cfg.ShutdownTimeout); err != nil && err != http.ErrServerClosed {
+		log.Fatal(err)
+	}
+	log.Println("Server shut down gracefully")
 }
 
-// Helper to check for /points endpoint of /receipts
+// Helper to dispatch the /points and /breakdown sub-endpoints of /receipts/{id}
 func GetPointsHandler(w http.ResponseWriter, r *http.Request) {
-	if strings.HasSuffix(r.URL.Path, "/points") {
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/points"):
 		GetPoints(w, r)
-	} else {
+	case strings.HasSuffix(r.URL.Path, "/breakdown"):
+		GetBreakdown(w, r)
+	default:
 		http.Error(w, "Not found", http.StatusNotFound)
 	}
 }
@@ -61,29 +112,45 @@ func GetPointsHandler(w http.ResponseWriter, r *http.Request) {
 // Process a receipt by reading in JSON data and calculating
 // the points. Save the info in the receipts map.
 func ProcessReceipt(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
 	var receipt Receipt
 
+	// Bound how much of the body we'll read so a hostile client can't
+	// stream an unbounded payload and exhaust memory.
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)
+
 	// If the receipt is invalid, return 400
 	err := json.NewDecoder(r.Body).Decode(&receipt)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		recordError("validation")
+		writeValidationError(w, []FieldError{{Field: "body", Message: err.Error()}})
 		return
 	}
 
-	// Protect receipts from concurrent modification
-	mutex.Lock()
-	defer mutex.Unlock()
+	if errs := validateReceipt(receipt); len(errs) > 0 {
+		recordError("validation")
+		writeValidationError(w, errs)
+		return
+	}
 
-	calculatePoints(&receipt)
+	total, breakdown := engine.Score(&receipt)
+	receipt.Points = total
+	recordScoring(time.Since(start), total, breakdown)
 
 	id, err := generateID()
 	// If there was an error generating the ID, return it
 	if err != nil {
+		recordError("internal")
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	receipt.ID = id
 
-	receipts[id] = receipt
+	if err := store.Save(id, receipt); err != nil {
+		recordError("internal")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
 	response := struct {
 		ID string `json:"id"`
@@ -94,6 +161,40 @@ func ProcessReceipt(w http.ResponseWriter, r *http.Request) {
 	jsonResponse(w, response)
 }
 
+// List receipts matching the query params, paged and sorted.
+func ListReceipts(w http.ResponseWriter, r *http.Request) {
+	var cmd ReceiptPagedRequestCommand
+	if err := cmd.LoadDataFromRequest(w, r); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	all, err := store.List()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	jsonResponse(w, cmd.Execute(all))
+}
+
+// Flag, archive, or delete a batch of receipts in one request.
+func BulkUpdateStatus(w http.ResponseWriter, r *http.Request) {
+	var cmd BulkStatusUpdateCommand
+	if err := cmd.LoadDataFromRequest(w, r); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := cmd.Execute(store)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	jsonResponse(w, result)
+}
+
 // Generate a random ID
 func generateID() (string, error) {
 	// Generate 16 random bytes
@@ -113,10 +214,11 @@ func generateID() (string, error) {
 func GetPoints(w http.ResponseWriter, r *http.Request) {
 	id := strings.Split(r.URL.Path, "/")[2]
 
-	mutex.Lock()
-	defer mutex.Unlock()
-
-	receipt, ok := receipts[id]
+	receipt, ok, err := store.Get(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 	if !ok {
 		http.Error(w, "Invalid receipt ID", http.StatusBadRequest)
 		return
@@ -126,63 +228,28 @@ func GetPoints(w http.ResponseWriter, r *http.Request) {
 	jsonResponse(w, response)
 }
 
-// Calculate the points of a receipt based on seven rules.
-func calculatePoints(receipt *Receipt) {
-	points := 0
-
-	// Rule 1: One point for every alphanumeric character in the retailer name.
-	retailer := receipt.Retailer
-	alphanumericRetailer := ""
-	for _, char := range retailer {
-		if unicode.IsLetter(char) || unicode.IsDigit(char) {
-			alphanumericRetailer += string(char)
-		}
-	}
-	points += len(alphanumericRetailer)
-
-	// Rule 2: 50 points if the total is a round dollar amount with no cents.
-	total, _ := strconv.ParseFloat(receipt.Total, 64)
-	if total == float64(int(total)) {
-		points += 50
-	}
-
-	// Rule 3: 25 points if the total is a multiple of 0.25.
-	if int(total*100)%25 == 0 {
-		points += 25
-	}
-
-	// Rule 4: 5 points for every two items on the receipt.
-	itemCount := len(receipt.Items)
-	pairCount := itemCount / 2
-	points += pairCount * 5
+// BreakdownResponse is the JSON shape returned by GET /receipts/{id}/breakdown.
+type BreakdownResponse struct {
+	Points    int          `json:"points"`
+	Breakdown []RuleResult `json:"breakdown"`
+}
 
-	// Rule 5: If the trimmed length of the item description is a multiple of 3,
-	// multiply the price by 0.2 and round up to the nearest integer. The result is the number of points earned.
-	for _, item := range receipt.Items {
-		trimmedLength := len(strings.TrimSpace(item.ShortDescription))
-		if trimmedLength%3 == 0 {
-			price, _ := strconv.ParseFloat(item.Price, 64)
-			itemPoints := int(math.Ceil(price * 0.2))
-			if itemPoints > 0 {
-				points += itemPoints
-			}
-		}
-	}
+// Return the per-rule points breakdown of a given receipt.
+func GetBreakdown(w http.ResponseWriter, r *http.Request) {
+	id := strings.Split(r.URL.Path, "/")[2]
 
-	// Rule 6: 6 points if the day in the purchase date is odd.
-	purchaseDate, _ := time.Parse("2006-01-02", receipt.PurchaseDate)
-	if purchaseDate.Day()%2 != 0 {
-		points += 6
+	receipt, ok, err := store.Get(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
-
-	// Rule 7: 10 points if the time of purchase is after 2:00pm and before 4:00pm.
-	purchaseTime, _ := time.Parse("15:04", receipt.PurchaseTime)
-	if purchaseTime.After(time.Date(purchaseTime.Year(), purchaseTime.Month(), purchaseTime.Day(), 14, 0, 0, 0, purchaseTime.Location())) &&
-		purchaseTime.Before(time.Date(purchaseTime.Year(), purchaseTime.Month(), purchaseTime.Day(), 16, 0, 0, 0, purchaseTime.Location())) {
-		points += 10
+	if !ok {
+		http.Error(w, "Invalid receipt ID", http.StatusBadRequest)
+		return
 	}
 
-	receipt.Points = points
+	total, breakdown := engine.Score(&receipt)
+	jsonResponse(w, BreakdownResponse{Points: total, Breakdown: breakdown})
 }
 
 // Utility function to send JSON-encoded responses in HTTP
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Rule scores one aspect of a receipt and explains why it awarded what it
+// awarded, so the breakdown endpoint can show more than just a number.
+type Rule interface {
+	Name() string
+	Apply(r *Receipt) (points int, explanation string)
+}
+
+// RuleResult is one line of a receipt's points breakdown.
+type RuleResult struct {
+	Rule        string `json:"rule"`
+	Points      int    `json:"points"`
+	Explanation string `json:"explanation"`
+}
+
+// RuleEngine runs a registered set of rules over a receipt and totals the
+// result. Rules are applied in registration order.
+type RuleEngine struct {
+	rules []Rule
+}
+
+// NewRuleEngine builds an engine from the given rules.
+func NewRuleEngine(rules ...Rule) *RuleEngine {
+	return &RuleEngine{rules: rules}
+}
+
+// Register appends a rule to the engine, for plugin-supplied rules loaded
+// after the built-ins.
+func (e *RuleEngine) Register(r Rule) {
+	e.rules = append(e.rules, r)
+}
+
+// Score runs every registered rule against the receipt, returning the total
+// points and a per-rule breakdown.
+func (e *RuleEngine) Score(r *Receipt) (int, []RuleResult) {
+	total := 0
+	breakdown := make([]RuleResult, 0, len(e.rules))
+
+	for _, rule := range e.rules {
+		points, explanation := rule.Apply(r)
+		total += points
+		breakdown = append(breakdown, RuleResult{
+			Rule:        rule.Name(),
+			Points:      points,
+			Explanation: explanation,
+		})
+	}
+
+	return total, breakdown
+}
+
+// BuiltinRules returns the seven rules from the original scorer, driven by
+// the given config so their constants can be tuned without recompiling.
+func BuiltinRules(cfg RulesConfig) []Rule {
+	return []Rule{
+		alphanumericRetailerRule{},
+		roundDollarRule{points: cfg.RoundDollarPoints},
+		quarterMultipleRule{points: cfg.QuarterMultiplePoints, multipleOf: cfg.QuarterMultipleOf},
+		itemPairRule{pointsPerPair: cfg.ItemPairPoints},
+		itemDescriptionRule{multipleOf: cfg.ItemDescriptionMultipleOf, priceFactor: cfg.ItemDescriptionPriceFactor},
+		oddDayRule{points: cfg.OddDayPoints},
+		afternoonRule{
+			points:    cfg.AfternoonPoints,
+			startHour: cfg.AfternoonWindowStartHour,
+			endHour:   cfg.AfternoonWindowEndHour,
+		},
+	}
+}
+
+// Rule 1: one point for every alphanumeric character in the retailer name.
+type alphanumericRetailerRule struct{}
+
+func (alphanumericRetailerRule) Name() string { return "alphanumericRetailer" }
+
+func (alphanumericRetailerRule) Apply(r *Receipt) (int, string) {
+	count := 0
+	for _, char := range r.Retailer {
+		if unicode.IsLetter(char) || unicode.IsDigit(char) {
+			count++
+		}
+	}
+	return count, "one point per alphanumeric character in the retailer name"
+}
+
+// Rule 2: points if the total is a round dollar amount with no cents.
+type roundDollarRule struct{ points int }
+
+func (roundDollarRule) Name() string { return "roundDollar" }
+
+func (r roundDollarRule) Apply(receipt *Receipt) (int, string) {
+	total, _ := strconv.ParseFloat(receipt.Total, 64)
+	if total == float64(int(total)) {
+		return r.points, "total is a round dollar amount with no cents"
+	}
+	return 0, "total has cents"
+}
+
+// Rule 3: points if the total is a multiple of the configured amount.
+type quarterMultipleRule struct {
+	points     int
+	multipleOf float64
+}
+
+func (quarterMultipleRule) Name() string { return "quarterMultiple" }
+
+func (r quarterMultipleRule) Apply(receipt *Receipt) (int, string) {
+	total, _ := strconv.ParseFloat(receipt.Total, 64)
+	cents := int(math.Round(total * 100))
+	step := int(math.Round(r.multipleOf * 100))
+	if step > 0 && cents%step == 0 {
+		return r.points, "total is a multiple of the configured amount"
+	}
+	return 0, "total is not a multiple of the configured amount"
+}
+
+// Rule 4: points for every two items on the receipt.
+type itemPairRule struct{ pointsPerPair int }
+
+func (itemPairRule) Name() string { return "itemPair" }
+
+func (r itemPairRule) Apply(receipt *Receipt) (int, string) {
+	pairs := len(receipt.Items) / 2
+	return pairs * r.pointsPerPair, "points awarded for every pair of items"
+}
+
+// Rule 5: if the trimmed item description length is a multiple of the
+// configured value, award price * factor points (rounded up).
+type itemDescriptionRule struct {
+	multipleOf  int
+	priceFactor float64
+}
+
+func (itemDescriptionRule) Name() string { return "itemDescription" }
+
+func (r itemDescriptionRule) Apply(receipt *Receipt) (int, string) {
+	if r.multipleOf == 0 {
+		return 0, "no description multiple configured"
+	}
+
+	total := 0
+	for _, item := range receipt.Items {
+		trimmedLength := len(strings.TrimSpace(item.ShortDescription))
+		if trimmedLength%r.multipleOf == 0 {
+			price, _ := strconv.ParseFloat(item.Price, 64)
+			itemPoints := int(math.Ceil(price * r.priceFactor))
+			if itemPoints > 0 {
+				total += itemPoints
+			}
+		}
+	}
+	return total, "price-derived points for items whose description length matches the configured multiple"
+}
+
+// Rule 6: points if the day in the purchase date is odd.
+type oddDayRule struct{ points int }
+
+func (oddDayRule) Name() string { return "oddDay" }
+
+func (r oddDayRule) Apply(receipt *Receipt) (int, string) {
+	purchaseDate, err := time.Parse("2006-01-02", receipt.PurchaseDate)
+	if err == nil && purchaseDate.Day()%2 != 0 {
+		return r.points, "purchase day is odd"
+	}
+	return 0, "purchase day is even"
+}
+
+// Rule 7: points if the time of purchase falls within the configured window.
+type afternoonRule struct {
+	points    int
+	startHour int
+	endHour   int
+}
+
+func (afternoonRule) Name() string { return "afternoonWindow" }
+
+func (r afternoonRule) Apply(receipt *Receipt) (int, string) {
+	purchaseTime, err := time.Parse("15:04", receipt.PurchaseTime)
+	if err != nil {
+		return 0, "purchase time could not be parsed"
+	}
+
+	start := time.Date(purchaseTime.Year(), purchaseTime.Month(), purchaseTime.Day(), r.startHour, 0, 0, 0, purchaseTime.Location())
+	end := time.Date(purchaseTime.Year(), purchaseTime.Month(), purchaseTime.Day(), r.endHour, 0, 0, 0, purchaseTime.Location())
+
+	if purchaseTime.After(start) && purchaseTime.Before(end) {
+		return r.points, "purchase time falls within the configured afternoon window"
+	}
+	return 0, "purchase time falls outside the configured afternoon window"
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// RulesConfig holds the tunable constants for the built-in rules, loaded
+// from a JSON file at startup so operators can adjust scoring without a
+// rebuild.
+type RulesConfig struct {
+	RoundDollarPoints          int     `json:"roundDollarPoints"`
+	QuarterMultiplePoints      int     `json:"quarterMultiplePoints"`
+	QuarterMultipleOf          float64 `json:"quarterMultipleOf"`
+	ItemPairPoints             int     `json:"itemPairPoints"`
+	ItemDescriptionMultipleOf  int     `json:"itemDescriptionMultipleOf"`
+	ItemDescriptionPriceFactor float64 `json:"itemDescriptionPriceFactor"`
+	OddDayPoints               int     `json:"oddDayPoints"`
+	AfternoonWindowStartHour   int     `json:"afternoonWindowStartHour"`
+	AfternoonWindowEndHour     int     `json:"afternoonWindowEndHour"`
+	AfternoonPoints            int     `json:"afternoonPoints"`
+}
+
+// DefaultRulesConfig returns the constants the scorer has always used.
+func DefaultRulesConfig() RulesConfig {
+	return RulesConfig{
+		RoundDollarPoints:          50,
+		QuarterMultiplePoints:      25,
+		QuarterMultipleOf:          0.25,
+		ItemPairPoints:             5,
+		ItemDescriptionMultipleOf:  3,
+		ItemDescriptionPriceFactor: 0.2,
+		OddDayPoints:               6,
+		AfternoonWindowStartHour:   14,
+		AfternoonWindowEndHour:     16,
+		AfternoonPoints:            10,
+	}
+}
+
+// LoadRulesConfig reads rule constants from a JSON file at path, applying
+// them on top of the defaults. An empty path, or a path that doesn't
+// exist, simply yields the defaults.
+func LoadRulesConfig(path string) (RulesConfig, error) {
+	cfg := DefaultRulesConfig()
+	if path == "" {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
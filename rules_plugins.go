@@ -0,0 +1,54 @@
+//go:build !windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"plugin"
+)
+
+// LoadRulePlugins scans dir for *.so files built with `go build
+// -buildmode=plugin` and loads the Rule each exports. Each plugin must
+// export a `NewRule() Rule` symbol. An empty dir is a no-op.
+func LoadRulePlugins(dir string) ([]Rule, error) {
+	if dir == "" {
+		return nil, nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []Rule
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".so" {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		p, err := plugin.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("loading plugin %s: %w", path, err)
+		}
+
+		sym, err := p.Lookup("NewRule")
+		if err != nil {
+			return nil, fmt.Errorf("plugin %s does not export NewRule: %w", path, err)
+		}
+
+		newRule, ok := sym.(func() Rule)
+		if !ok {
+			return nil, fmt.Errorf("plugin %s: NewRule has the wrong signature", path)
+		}
+
+		rules = append(rules, newRule())
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,9 @@
+//go:build windows
+
+package main
+
+// LoadRulePlugins is a no-op on Windows: the plugin package only supports
+// linux, freebsd, and darwin, so there is no dynamic loading to do here.
+func LoadRulePlugins(dir string) ([]Rule, error) {
+	return nil, nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import "testing"
+
+func sampleReceipt() *Receipt {
+	return &Receipt{
+		Retailer:     "Target",
+		PurchaseDate: "2022-01-01",
+		PurchaseTime: "13:01",
+		Total:        "35.35",
+		Items: []Item{
+			{ShortDescription: "Mountain Dew 12PK", Price: "6.49"},
+			{ShortDescription: "Emils Cheese Pizza", Price: "12.25"},
+			{ShortDescription: "Knorr Creamy Chicken", Price: "1.26"},
+			{ShortDescription: "Doritos Nacho Cheese", Price: "3.35"},
+			{ShortDescription: "   Klarbrunn 12-PK 12 FL OZ  ", Price: "12.00"},
+		},
+	}
+}
+
+func TestAlphanumericRetailerRule(t *testing.T) {
+	points, _ := alphanumericRetailerRule{}.Apply(sampleReceipt())
+	if points != 6 {
+		t.Errorf("got %d points, want 6", points)
+	}
+}
+
+func TestRoundDollarRule(t *testing.T) {
+	rule := roundDollarRule{points: 50}
+	if points, _ := rule.Apply(&Receipt{Total: "10.00"}); points != 50 {
+		t.Errorf("round total: got %d, want 50", points)
+	}
+	if points, _ := rule.Apply(&Receipt{Total: "10.01"}); points != 0 {
+		t.Errorf("non-round total: got %d, want 0", points)
+	}
+}
+
+func TestQuarterMultipleRule(t *testing.T) {
+	rule := quarterMultipleRule{points: 25, multipleOf: 0.25}
+	if points, _ := rule.Apply(&Receipt{Total: "10.25"}); points != 25 {
+		t.Errorf("multiple of 0.25: got %d, want 25", points)
+	}
+	if points, _ := rule.Apply(&Receipt{Total: "10.10"}); points != 0 {
+		t.Errorf("not a multiple of 0.25: got %d, want 0", points)
+	}
+}
+
+func TestItemPairRule(t *testing.T) {
+	rule := itemPairRule{pointsPerPair: 5}
+	points, _ := rule.Apply(sampleReceipt())
+	if points != 10 {
+		t.Errorf("got %d points for 5 items, want 10", points)
+	}
+}
+
+func TestItemDescriptionRule(t *testing.T) {
+	rule := itemDescriptionRule{multipleOf: 3, priceFactor: 0.2}
+	points, _ := rule.Apply(sampleReceipt())
+	if points != 6 {
+		t.Errorf("got %d points, want 6", points)
+	}
+}
+
+func TestOddDayRule(t *testing.T) {
+	rule := oddDayRule{points: 6}
+	if points, _ := rule.Apply(&Receipt{PurchaseDate: "2022-01-01"}); points != 6 {
+		t.Errorf("odd day: got %d, want 6", points)
+	}
+	if points, _ := rule.Apply(&Receipt{PurchaseDate: "2022-01-02"}); points != 0 {
+		t.Errorf("even day: got %d, want 0", points)
+	}
+}
+
+func TestAfternoonRule(t *testing.T) {
+	rule := afternoonRule{points: 10, startHour: 14, endHour: 16}
+	if points, _ := rule.Apply(&Receipt{PurchaseTime: "14:33"}); points != 10 {
+		t.Errorf("in window: got %d, want 10", points)
+	}
+	if points, _ := rule.Apply(&Receipt{PurchaseTime: "13:01"}); points != 0 {
+		t.Errorf("out of window: got %d, want 0", points)
+	}
+}
+
+func TestRuleEngineScoreTotalsMatchBreakdown(t *testing.T) {
+	engine := NewRuleEngine(BuiltinRules(DefaultRulesConfig())...)
+	receipt := sampleReceipt()
+
+	total, breakdown := engine.Score(receipt)
+
+	if len(breakdown) != 7 {
+		t.Fatalf("expected 7 rule results, got %d", len(breakdown))
+	}
+
+	sum := 0
+	for _, result := range breakdown {
+		sum += result.Points
+	}
+	if sum != total {
+		t.Fatalf("breakdown sums to %d but total is %d", sum, total)
+	}
+	if total != 28 {
+		t.Fatalf("got total %d, want 28", total)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultReadTimeout       = 10 * time.Second
+	defaultReadHeaderTimeout = 5 * time.Second
+	defaultWriteTimeout      = 20 * time.Second
+	defaultIdleTimeout       = 60 * time.Second
+	defaultMaxHeaderBytes    = 1 << 20 // 1 MB
+	defaultShutdownTimeout   = 15 * time.Second
+
+	// maxRequestBodyBytes bounds how much of a request body we'll read,
+	// so a client can't stream an unbounded body to exhaust memory.
+	maxRequestBodyBytes = 1 << 20 // 1 MB
+)
+
+// serverConfig holds the tunable http.Server settings, each overridable
+// via an environment variable.
+type serverConfig struct {
+	Addr              string
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	MaxHeaderBytes    int
+	ShutdownTimeout   time.Duration
+}
+
+// serverConfigFromEnv resolves serverConfig from the environment, falling
+// back to safe defaults for anything unset.
+func serverConfigFromEnv() serverConfig {
+	return serverConfig{
+		Addr:              envString("SERVER_ADDR", ":8000"),
+		ReadTimeout:       envDuration("SERVER_READ_TIMEOUT", defaultReadTimeout),
+		ReadHeaderTimeout: envDuration("SERVER_READ_HEADER_TIMEOUT", defaultReadHeaderTimeout),
+		WriteTimeout:      envDuration("SERVER_WRITE_TIMEOUT", defaultWriteTimeout),
+		IdleTimeout:       envDuration("SERVER_IDLE_TIMEOUT", defaultIdleTimeout),
+		MaxHeaderBytes:    envInt("SERVER_MAX_HEADER_BYTES", defaultMaxHeaderBytes),
+		ShutdownTimeout:   envDuration("SERVER_SHUTDOWN_TIMEOUT", defaultShutdownTimeout),
+	}
+}
+
+func envString(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	if v := os.Getenv(key); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+	return fallback
+}
+
+func envInt(key string, fallback int) int {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return fallback
+}
+
+// runServer starts srv and blocks until a SIGINT/SIGTERM triggers a
+// graceful shutdown bounded by shutdownTimeout.
+func runServer(srv *http.Server, shutdownTimeout time.Duration) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return srv.Shutdown(shutdownCtx)
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"math"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// statsTickInterval is how often each EWMA decays towards the current
+// instantaneous rate.
+const statsTickInterval = 5 * time.Second
+
+// EWMA is an exponentially-weighted moving average over a fixed window:
+// each tick blends in the rate observed since the last tick.
+type EWMA struct {
+	mu          sync.Mutex
+	uncounted   int64
+	rate        float64
+	initialized bool
+	alpha       float64
+}
+
+// NewEWMA builds an EWMA that weights its window's worth of history.
+func NewEWMA(window time.Duration) *EWMA {
+	alpha := 1 - math.Exp(-statsTickInterval.Seconds()/window.Seconds())
+	return &EWMA{alpha: alpha}
+}
+
+// Update records n events since the last tick.
+func (e *EWMA) Update(n int64) {
+	e.mu.Lock()
+	e.uncounted += n
+	e.mu.Unlock()
+}
+
+// tick decays the rate towards the instantaneous rate observed this
+// interval. Call it once per statsTickInterval.
+func (e *EWMA) tick() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	instantRate := float64(e.uncounted) / statsTickInterval.Seconds()
+	e.uncounted = 0
+
+	if e.initialized {
+		e.rate += e.alpha * (instantRate - e.rate)
+	} else {
+		e.rate = instantRate
+		e.initialized = true
+	}
+}
+
+// Rate returns the current decayed rate, in events per second.
+func (e *EWMA) Rate() float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.rate
+}
+
+// RuleStat tracks lifetime totals and rolling points-per-second rates for
+// one scoring rule.
+type RuleStat struct {
+	Count  uint64
+	Points int64
+
+	rate1m *EWMA
+	rate5m *EWMA
+	rate1h *EWMA
+}
+
+// RuleStatSnapshot is the JSON-serializable view of a RuleStat.
+type RuleStatSnapshot struct {
+	Count             uint64  `json:"count"`
+	Points            int64   `json:"points"`
+	PointsPerSecond1m float64 `json:"pointsPerSecond1m"`
+	PointsPerSecond5m float64 `json:"pointsPerSecond5m"`
+	PointsPerSecond1h float64 `json:"pointsPerSecond1h"`
+}
+
+// StatsRegistry aggregates rolling statistics per rule name, each with its
+// own running count, total, and decayed rate.
+type StatsRegistry struct {
+	mu    sync.Mutex
+	rules map[string]*RuleStat
+}
+
+// NewStatsRegistry returns an empty registry.
+func NewStatsRegistry() *StatsRegistry {
+	return &StatsRegistry{rules: make(map[string]*RuleStat)}
+}
+
+// Record attributes points awarded by rule to the registry, creating the
+// rule's entry on first use.
+func (s *StatsRegistry) Record(rule string, points int) {
+	s.mu.Lock()
+	rs, ok := s.rules[rule]
+	if !ok {
+		rs = &RuleStat{
+			rate1m: NewEWMA(time.Minute),
+			rate5m: NewEWMA(5 * time.Minute),
+			rate1h: NewEWMA(time.Hour),
+		}
+		s.rules[rule] = rs
+	}
+	s.mu.Unlock()
+
+	atomic.AddUint64(&rs.Count, 1)
+	atomic.AddInt64(&rs.Points, int64(points))
+	rs.rate1m.Update(int64(points))
+	rs.rate5m.Update(int64(points))
+	rs.rate1h.Update(int64(points))
+}
+
+// Start runs the background ticker that decays every rule's EWMAs. It
+// never returns and should be called in its own goroutine.
+func (s *StatsRegistry) Start() {
+	ticker := time.NewTicker(statsTickInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.mu.Lock()
+		for _, rs := range s.rules {
+			rs.rate1m.tick()
+			rs.rate5m.tick()
+			rs.rate1h.tick()
+		}
+		s.mu.Unlock()
+	}
+}
+
+// Snapshot returns a point-in-time copy of every rule's stats, safe to
+// encode as JSON.
+func (s *StatsRegistry) Snapshot() map[string]RuleStatSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make(map[string]RuleStatSnapshot, len(s.rules))
+	for name, rs := range s.rules {
+		out[name] = RuleStatSnapshot{
+			Count:             atomic.LoadUint64(&rs.Count),
+			Points:            atomic.LoadInt64(&rs.Points),
+			PointsPerSecond1m: rs.rate1m.Rate(),
+			PointsPerSecond5m: rs.rate5m.Rate(),
+			PointsPerSecond1h: rs.rate1h.Rate(),
+		}
+	}
+	return out
+}
+
+var globalStats = NewStatsRegistry()
+
+// StatsResponse is the JSON body returned by GET /stats.
+type StatsResponse struct {
+	Rules map[string]RuleStatSnapshot `json:"rules"`
+}
+
+// GetStats reports rolling per-rule statistics.
+func GetStats(w http.ResponseWriter, r *http.Request) {
+	jsonResponse(w, StatsResponse{Rules: globalStats.Snapshot()})
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// Store persists receipts so that restarting the service does not lose
+// state and so the service can eventually run as more than one process.
+type Store interface {
+	Save(id string, r Receipt) error
+	Get(id string) (Receipt, bool, error)
+	List() ([]Receipt, error)
+	Delete(id string) error
+}
+
+// NewStore builds a Store for the given backend name ("memory", "bolt").
+// path is only used by backends that need a file on disk.
+func NewStore(backend, path string) (Store, error) {
+	switch backend {
+	case "", "memory":
+		return NewMemoryStore(), nil
+	case "bolt":
+		return NewBoltStore(path)
+	default:
+		return nil, fmt.Errorf("unknown store backend %q", backend)
+	}
+}
+
+// storeBackendFromEnv resolves the backend/path pair from the environment,
+// falling back to the in-memory store when nothing is configured.
+func storeBackendFromEnv() (backend, path string) {
+	backend = os.Getenv("RECEIPT_STORE_BACKEND")
+	path = os.Getenv("RECEIPT_STORE_PATH")
+	if path == "" {
+		path = "receipts.db"
+	}
+	return backend, path
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var receiptsBucket = []byte("receipts")
+
+// BoltStore is an embedded on-disk Store backed by BoltDB. It trades the
+// simplicity of MemoryStore for durability: receipts survive a process
+// restart and the database is a single file that's trivial to back up.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// NewBoltStore opens (creating if necessary) a BoltDB file at path and
+// ensures the receipts bucket exists.
+func NewBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(receiptsBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &BoltStore{db: db}, nil
+}
+
+func (s *BoltStore) Save(id string, r Receipt) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(receiptsBucket).Put([]byte(id), data)
+	})
+}
+
+func (s *BoltStore) Get(id string) (Receipt, bool, error) {
+	var r Receipt
+	var found bool
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(receiptsBucket).Get([]byte(id))
+		if data == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(data, &r)
+	})
+
+	return r, found, err
+}
+
+func (s *BoltStore) Delete(id string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(receiptsBucket).Delete([]byte(id))
+	})
+}
+
+func (s *BoltStore) List() ([]Receipt, error) {
+	var out []Receipt
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(receiptsBucket).ForEach(func(_, data []byte) error {
+			var r Receipt
+			if err := json.Unmarshal(data, &r); err != nil {
+				return err
+			}
+			out = append(out, r)
+			return nil
+		})
+	})
+
+	return out, err
+}
+
+// Close releases the underlying database file.
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,46 @@
+package main
+
+import "sync"
+
+// MemoryStore is the original in-memory map, kept around for tests and for
+// local development where persistence across restarts doesn't matter.
+type MemoryStore struct {
+	mutex    sync.Mutex
+	receipts map[string]Receipt
+}
+
+// NewMemoryStore returns an empty in-memory Store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{receipts: make(map[string]Receipt)}
+}
+
+func (s *MemoryStore) Save(id string, r Receipt) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.receipts[id] = r
+	return nil
+}
+
+func (s *MemoryStore) Get(id string) (Receipt, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	r, ok := s.receipts[id]
+	return r, ok, nil
+}
+
+func (s *MemoryStore) Delete(id string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.receipts, id)
+	return nil
+}
+
+func (s *MemoryStore) List() ([]Receipt, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	out := make([]Receipt, 0, len(s.receipts))
+	for _, r := range s.receipts {
+		out = append(out, r)
+	}
+	return out, nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import "testing"
+
+func TestMemoryStoreSaveGet(t *testing.T) {
+	s := NewMemoryStore()
+
+	if _, ok, _ := s.Get("missing"); ok {
+		t.Fatal("expected Get of an unknown id to report not found")
+	}
+
+	want := Receipt{ID: "abc", Retailer: "Target", Points: 42}
+	if err := s.Save("abc", want); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	got, ok, err := s.Get("abc")
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected Get to find the saved receipt")
+	}
+	if got.ID != want.ID || got.Retailer != want.Retailer || got.Points != want.Points {
+		t.Fatalf("Get returned %+v, want %+v", got, want)
+	}
+}
+
+func TestMemoryStoreDelete(t *testing.T) {
+	s := NewMemoryStore()
+	s.Save("abc", Receipt{ID: "abc"})
+
+	if err := s.Delete("abc"); err != nil {
+		t.Fatalf("Delete returned error: %v", err)
+	}
+
+	if _, ok, _ := s.Get("abc"); ok {
+		t.Fatal("expected receipt to be gone after Delete")
+	}
+}
+
+func TestMemoryStoreList(t *testing.T) {
+	s := NewMemoryStore()
+	s.Save("a", Receipt{ID: "a"})
+	s.Save("b", Receipt{ID: "b"})
+
+	all, err := s.List()
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("List returned %d receipts, want 2", len(all))
+	}
+}
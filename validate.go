@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// FieldError describes one invalid field of a receipt.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationErrorResponse is the JSON body returned for a 400 response.
+type ValidationErrorResponse struct {
+	Errors []FieldError `json:"errors"`
+}
+
+var decimalPattern = regexp.MustCompile(`^\d+(\.\d{1,2})?$`)
+
+// validateReceipt checks the receipt shape the scorer relies on, so that a
+// malformed total or date fails loudly instead of silently scoring as zero.
+func validateReceipt(r Receipt) []FieldError {
+	var errs []FieldError
+
+	if strings.TrimSpace(r.Retailer) == "" {
+		errs = append(errs, FieldError{Field: "retailer", Message: "must not be empty"})
+	}
+
+	if _, err := time.Parse("2006-01-02", r.PurchaseDate); err != nil {
+		errs = append(errs, FieldError{Field: "purchaseDate", Message: "must match 2006-01-02"})
+	}
+
+	if _, err := time.Parse("15:04", r.PurchaseTime); err != nil {
+		errs = append(errs, FieldError{Field: "purchaseTime", Message: "must match 15:04"})
+	}
+
+	if !decimalPattern.MatchString(r.Total) {
+		errs = append(errs, FieldError{Field: "total", Message: "must be a non-negative decimal with at most two fractional digits"})
+	}
+
+	if len(r.Items) == 0 {
+		errs = append(errs, FieldError{Field: "items", Message: "must not be empty"})
+	}
+
+	for i, item := range r.Items {
+		if !decimalPattern.MatchString(item.Price) {
+			errs = append(errs, FieldError{
+				Field:   fmt.Sprintf("items[%d].price", i),
+				Message: "must be a non-negative decimal with at most two fractional digits",
+			})
+		}
+	}
+
+	return errs
+}
+
+// writeValidationError responds with a 400 and the given field errors in
+// the shape described by the API contract.
+func writeValidationError(w http.ResponseWriter, errs []FieldError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(ValidationErrorResponse{Errors: errs})
+}